@@ -9,7 +9,7 @@ type Chip struct {
 	comments []Comment
 
 	// declaration
-	start, end token.Position
+	start, end token.Pos
 	name       string
 
 	// header
@@ -24,7 +24,7 @@ type Chip struct {
 // All comments are taken into account
 // so this is not necessarily the position of the main comment
 // or where the chip is declared.
-func (c Chip) Start() token.Position {
+func (c Chip) Start() token.Pos {
 	if len(c.comments) == 0 {
 		return c.start
 	}
@@ -32,7 +32,11 @@ func (c Chip) Start() token.Position {
 }
 
 // End returns the line at which the chip ends.
-func (c Chip) End() token.Position { return c.end }
+func (c Chip) End() token.Pos { return c.end }
+
+// DeclStart returns the position of the CHIP keyword itself,
+// ignoring any leading comments (unlike Start).
+func (c Chip) DeclStart() token.Pos { return c.start }
 
 // NumComments returns the number of comments relative to the chip.
 // In the hdl format, these are all comments before the chip declaration.
@@ -49,37 +53,55 @@ func (c Chip) Name() string { return c.name }
 func (c Chip) Inputs() InputList { return c.inputs }
 
 // Outputs returns the list of output pins.
-func (c Chip) Outputs(i int) OutputList { return c.outputs }
+func (c Chip) Outputs() OutputList { return c.outputs }
 
 // Parts returns the list of part.
 func (c Chip) Parts() PartList { return c.parts }
 
 // ChipBuilder can build a description of a chip on-the-fly.
-type ChipBuilder struct{}
+type ChipBuilder struct {
+	chip Chip
+}
 
 // AppendComment appends a comment.
 // Even if comments relative to the chip are before the chip declaration,
 // it can be called after the declaration.
-func (b *ChipBuilder) AppendComment(comment Comment) {}
+func (b *ChipBuilder) AppendComment(comment Comment) {
+	b.chip.comments = append(b.chip.comments, comment)
+}
 
-// Declare starts the declaration of the chip and set its name.
-func (b *ChipBuilder) Declare(line int, name string) {}
+// Declare starts the declaration of the chip, setting its name and its span.
+func (b *ChipBuilder) Declare(start, end token.Pos, name string) {
+	b.chip.start = start
+	b.chip.end = end
+	b.chip.name = name
+}
 
 // DeclareInputs declare the list of inputs.
 // Even if inputs should be declared between the chip and outputs declarations,
 // it can be called at any moment.
-func (b *ChipBuilder) DeclareInputs(inputs InputList) {}
+func (b *ChipBuilder) DeclareInputs(inputs InputList) {
+	b.chip.inputs = inputs
+}
 
 // DeclareOutputs declares the list of outputs.
 // Even if outputs should be declared between the inputs and the body declarationw,
 // it can be called at any moment.
-func (b *ChipBuilder) DeclareOutputs(outputs OutputList) {}
+func (b *ChipBuilder) DeclareOutputs(outputs OutputList) {
+	b.chip.outputs = outputs
+}
 
-// DeclareParts declares the list of parts.
+// AppendPart appends a part to the body of the chip.
 // Even if the body of the chip should be defined after the outputs declaration,
 // it can be called at any moment.
-func (b *ChipBuilder) DeclareParts(part Part) {}
+func (b *ChipBuilder) AppendPart(part Part) {
+	b.chip.parts = append(b.chip.parts, part)
+}
 
 // Build return the chip.
 // The builder is reset so it can reused without any side effect on previously built chips.
-func (b *ChipBuilder) Build() Chip { return Chip{} }
+func (b *ChipBuilder) Build() Chip {
+	chip := b.chip
+	b.chip = Chip{}
+	return chip
+}
@@ -9,15 +9,21 @@ import (
 // Comment is a documentation associated to an element of the chip
 // or the chip itsefl.
 type Comment struct {
-	start, end token.Position
+	start, end token.Pos
 	lit        string
 }
 
+// NewComment returns a comment spanning from start to end, with lit as its full literal text
+// (including delimiters).
+func NewComment(start, end token.Pos, lit string) Comment {
+	return Comment{start: start, end: end, lit: lit}
+}
+
 // Start returns the position at which the comment starts.
-func (c Comment) Start() token.Position { return c.start }
+func (c Comment) Start() token.Pos { return c.start }
 
 // End returns the position of the end of the comment.
-func (c Comment) End() token.Position { return c.end }
+func (c Comment) End() token.Pos { return c.end }
 
 // Literal returns the full text of the comment, including the delimiters.
 func (c Comment) Literal() string { return c.lit }
@@ -4,19 +4,26 @@ import "github.com/batiazinga/hdl/iohdl/internal/token"
 
 // Input is the description of an input.
 type Input struct {
-	start, end token.Position
+	start, end token.Pos
 	name       string
+	size       int
+}
+
+// NewInput returns an input pin named name, spanning from start to end.
+// A size of 1 describes a single bit; anything greater describes a bus.
+func NewInput(start, end token.Pos, name string, size int) Input {
+	return Input{start: start, end: end, name: name, size: size}
 }
 
 // Start returns the position at which the input starts.
 // Comments are taken into account
 // so this is not necessarily the position of the input's name.
-func (in Input) Start() token.Position {}
+func (in Input) Start() token.Pos { return in.start }
 
 // End returns the line at which the input ends.
 // Comments are taken into account
 // so this is not necessarily the end of the input's name.
-func (in Input) End() token.Position { return in.end }
+func (in Input) End() token.Pos { return in.end }
 
 // Name returns the name of the input.
 func (in Input) Name() string { return in.name }
@@ -24,4 +31,18 @@ func (in Input) Name() string { return in.name }
 // Size returns the number of bits in the input.
 // An input is made of one or more bits.
 // A bus has more than one bit.
-func (in Input) Size() int { return 1 }
+func (in Input) Size() int {
+	if in.size <= 0 {
+		return 1
+	}
+	return in.size
+}
+
+// InputList is a list of inputs, sorted by position.
+type InputList []Input
+
+// Len returns the number of inputs in the list.
+func (l InputList) Len() int { return len(l) }
+
+// At returns the i-th input of the list.
+func (l InputList) At(i int) Input { return l[i] }
@@ -0,0 +1,48 @@
+package description
+
+import "github.com/batiazinga/hdl/iohdl/internal/token"
+
+// Output is the description of an output.
+type Output struct {
+	start, end token.Pos
+	name       string
+	size       int
+}
+
+// NewOutput returns an output pin named name, spanning from start to end.
+// A size of 1 describes a single bit; anything greater describes a bus.
+func NewOutput(start, end token.Pos, name string, size int) Output {
+	return Output{start: start, end: end, name: name, size: size}
+}
+
+// Start returns the position at which the output starts.
+// Comments are taken into account
+// so this is not necessarily the position of the output's name.
+func (out Output) Start() token.Pos { return out.start }
+
+// End returns the position at which the output ends.
+// Comments are taken into account
+// so this is not necessarily the end of the output's name.
+func (out Output) End() token.Pos { return out.end }
+
+// Name returns the name of the output.
+func (out Output) Name() string { return out.name }
+
+// Size returns the number of bits in the output.
+// An output is made of one or more bits.
+// A bus has more than one bit.
+func (out Output) Size() int {
+	if out.size <= 0 {
+		return 1
+	}
+	return out.size
+}
+
+// OutputList is a list of outputs, sorted by position.
+type OutputList []Output
+
+// Len returns the number of outputs in the list.
+func (l OutputList) Len() int { return len(l) }
+
+// At returns the i-th output of the list.
+func (l OutputList) At(i int) Output { return l[i] }
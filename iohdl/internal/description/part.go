@@ -0,0 +1,59 @@
+package description
+
+import "github.com/batiazinga/hdl/iohdl/internal/token"
+
+// Connection binds one pin of a part to a pin (or literal) of the enclosing chip,
+// as in "partPin=chipPin" inside a PARTS declaration.
+type Connection struct {
+	partPin, chipPin string
+}
+
+// NewConnection returns the connection partPin=chipPin.
+func NewConnection(partPin, chipPin string) Connection {
+	return Connection{partPin: partPin, chipPin: chipPin}
+}
+
+// PartPin returns the name of the pin on the part side of the connection.
+func (c Connection) PartPin() string { return c.partPin }
+
+// ChipPin returns the name of the pin (or literal) on the chip side of the connection.
+func (c Connection) ChipPin() string { return c.chipPin }
+
+// Part is the description of a part, i.e. a single line inside a PARTS declaration.
+type Part struct {
+	start, end  token.Pos
+	name        string
+	connections []Connection
+}
+
+// NewPart returns a part of chip type name with the given connections, spanning from start to end.
+func NewPart(start, end token.Pos, name string, connections []Connection) Part {
+	return Part{start: start, end: end, name: name, connections: connections}
+}
+
+// Start returns the position at which the part starts.
+// Comments are taken into account
+// so this is not necessarily the position of the part's name.
+func (p Part) Start() token.Pos { return p.start }
+
+// End returns the position at which the part ends.
+func (p Part) End() token.Pos { return p.end }
+
+// Name returns the name of the chip type this part instantiates.
+func (p Part) Name() string { return p.name }
+
+// NumConnections returns the number of connections of the part.
+func (p Part) NumConnections() int { return len(p.connections) }
+
+// Connection returns the i-th connection of the part.
+// This panics if i is out of bounds.
+func (p Part) Connection(i int) Connection { return p.connections[i] }
+
+// PartList is a list of parts, sorted by position.
+type PartList []Part
+
+// Len returns the number of parts in the list.
+func (l PartList) Len() int { return len(l) }
+
+// At returns the i-th part of the list.
+func (l PartList) At(i int) Part { return l[i] }
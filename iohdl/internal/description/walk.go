@@ -0,0 +1,84 @@
+package description
+
+import (
+	"sort"
+
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+// Node is implemented by every element of a description that has a position:
+// Chip, Input, Output, Part and Comment.
+type Node interface {
+	Start() token.Pos
+	End() token.Pos
+}
+
+// Visitor visits nodes of a description.
+// Visit is called for a node before its children are visited.
+// If the returned Visitor w is not nil, Walk visits each child of node with w,
+// followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a description in source order (interleaving comments
+// at their real position rather than only the leading batch on Chip),
+// calling v.Visit for node and each of its children.
+//
+// It is analogous to go/ast.Walk.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case Chip:
+		for _, child := range chipChildren(n) {
+			Walk(v, child)
+		}
+	case Input, Output, Part, Comment:
+		// leaf nodes: nothing to recurse into
+	}
+
+	v.Visit(nil)
+}
+
+// chipChildren returns every direct child of c (inputs, outputs, parts and comments),
+// sorted by their start position so Walk visits them in source order.
+func chipChildren(c Chip) []Node {
+	children := make([]Node, 0, c.inputs.Len()+c.outputs.Len()+c.parts.Len()+len(c.comments))
+	for i := 0; i < c.inputs.Len(); i++ {
+		children = append(children, c.inputs.At(i))
+	}
+	for i := 0; i < c.outputs.Len(); i++ {
+		children = append(children, c.outputs.At(i))
+	}
+	for i := 0; i < c.parts.Len(); i++ {
+		children = append(children, c.parts.At(i))
+	}
+	for _, comment := range c.comments {
+		children = append(children, comment)
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Start() < children[j].Start()
+	})
+	return children
+}
+
+// inspector adapts a plain func(Node) bool into a Visitor.
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a description in source order like Walk,
+// calling f for node and each of its children.
+// Inspect stops descending into a subtree as soon as f returns false for its root.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
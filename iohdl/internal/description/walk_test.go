@@ -0,0 +1,37 @@
+package description_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/batiazinga/hdl/iohdl/internal/description"
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+func TestWalkVisitsChildrenInSourceOrder(t *testing.T) {
+	var b description.ChipBuilder
+	b.Declare(token.Pos(0), token.Pos(50), "And")
+	b.DeclareInputs(description.InputList{
+		description.NewInput(token.Pos(10), token.Pos(11), "a", 1),
+		description.NewInput(token.Pos(12), token.Pos(13), "b", 1),
+	})
+	b.DeclareOutputs(description.OutputList{
+		description.NewOutput(token.Pos(20), token.Pos(23), "out", 1),
+	})
+	b.AppendPart(description.NewPart(token.Pos(30), token.Pos(40), "Nand", nil))
+	chip := b.Build()
+
+	var kinds []string
+	description.Inspect(chip, func(n description.Node) bool {
+		if n == nil {
+			return false
+		}
+		kinds = append(kinds, reflect.TypeOf(n).Name())
+		return true
+	})
+
+	want := []string{"Chip", "Input", "Input", "Output", "Part"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("unexpected visit order: %v instead of %v", kinds, want)
+	}
+}
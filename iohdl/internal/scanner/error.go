@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+// Error describes an error encountered while scanning: a position and a message.
+type Error struct {
+	Pos token.Position
+	Msg string
+}
+
+func (e Error) Error() string { return fmt.Sprintf("%s: %s", e.Pos, e.Msg) }
+
+// ErrorList is a list of *Error. Add appends in call order;
+// Sort and RemoveMultiples order the list by position.
+type ErrorList []*Error
+
+// Add appends an error at pos with the given message.
+func (l *ErrorList) Add(pos token.Position, msg string) {
+	*l = append(*l, &Error{pos, msg})
+}
+
+// Len, Swap and Less make ErrorList sortable by position.
+func (l ErrorList) Len() int      { return len(l) }
+func (l ErrorList) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l ErrorList) Less(i, j int) bool {
+	if l[i].Pos.Filename() != l[j].Pos.Filename() {
+		return l[i].Pos.Filename() < l[j].Pos.Filename()
+	}
+	if l[i].Pos.Line() != l[j].Pos.Line() {
+		return l[i].Pos.Line() < l[j].Pos.Line()
+	}
+	if l[i].Pos.Column() != l[j].Pos.Column() {
+		return l[i].Pos.Column() < l[j].Pos.Column()
+	}
+	return l[i].Msg < l[j].Msg
+}
+
+// Sort sorts the error list by position.
+func (l ErrorList) Sort() { sort.Sort(l) }
+
+// RemoveMultiples sorts the error list and removes duplicate line reports,
+// i.e. for a given line, only the first error is kept.
+// This mirrors the behavior of go/scanner.ErrorList.RemoveMultiples.
+func (l *ErrorList) RemoveMultiples() {
+	l.Sort()
+	lastFile := ""
+	last := -1
+	i := 0
+	for _, e := range *l {
+		if e.Pos.Filename() != lastFile || e.Pos.Line() != last {
+			lastFile = e.Pos.Filename()
+			last = e.Pos.Line()
+			(*l)[i] = e
+			i++
+		}
+	}
+	*l = (*l)[:i]
+}
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
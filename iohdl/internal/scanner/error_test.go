@@ -0,0 +1,29 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+// TestRemoveMultiplesAcrossFiles checks that RemoveMultiples does not collapse
+// two distinct errors that happen to share a line number but belong to
+// different files, the way go/scanner.ErrorList.RemoveMultiples wouldn't.
+func TestRemoveMultiplesAcrossFiles(t *testing.T) {
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.hdl", 10)
+	b := fset.AddFile("b.hdl", 10)
+
+	var errs ErrorList
+	errs.Add(a.Position(0), "bad pin in a.hdl")
+	errs.Add(b.Position(0), "bad pin in b.hdl")
+
+	errs.RemoveMultiples()
+
+	if len(errs) != 2 {
+		t.Fatalf("unexpected number of errors: %d instead of 2 (%v)", len(errs), errs)
+	}
+	if errs[0].Pos.Filename() != "a.hdl" || errs[1].Pos.Filename() != "b.hdl" {
+		t.Errorf("unexpected file order: %q then %q", errs[0].Pos.Filename(), errs[1].Pos.Filename())
+	}
+}
@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"fmt"
 	"unicode"
 	"unicode/utf8"
 
@@ -11,45 +12,161 @@ const (
 	eof = -1
 )
 
+// ErrorHandler is called for every illegal token or invalid encoding the scanner encounters,
+// with the position of the offending input and a message describing the problem.
+type ErrorHandler func(pos token.Position, msg string)
+
+// Mode is a set of flags (or 0) controlling Scanner behavior, following the go/scanner convention.
+type Mode uint
+
+const (
+	// ScanComments instructs Scan to return token.COMMENT tokens.
+	// Without it, comments are skipped so parsers don't have to filter them out themselves.
+	ScanComments Mode = 1 << iota
+
+	// SkipIllegal instructs Scan to silently skip over ILLEGAL tokens
+	// instead of returning them to the caller.
+	SkipIllegal
+)
+
 // Scanner can scan a source text to extract its tokens.
+// Positions are returned as token.Pos, resolved through file.
 type Scanner struct {
-	// input file
-	filename string
-	src      []byte
+	// input file and its source
+	file *token.File
+	src  []byte
+
+	// current rune, its width, and its byte offset in src
+	current rune
+	w       int
+	pos     int
+
+	// byte offset of the start of the current token
+	start int
+
+	// mode flags controlling what Scan returns
+	mode Mode
+
+	// errors encountered while scanning, and the handler notified about them, if any
+	errHandler ErrorHandler
+	errs       ErrorList
+
+	// one-token lookahead buffer, filled by Peek and Unread
+	buffered bool
+	bufPos   token.Pos
+	bufTok   token.Token
+	bufLit   string
+
+	// last token returned by Scan, remembered so Unread can push it back
+	lastPos token.Pos
+	lastTok token.Token
+	lastLit string
+}
 
-	// current rune and its width and position
-	// line and column start at zero
-	current        rune
-	w              int
-	pos, line, col int
+// Option configures a Scanner created by New.
+type Option func(*Scanner)
 
-	// start position of the current token
-	start, tokLine, tokCol int
+// WithErrorHandler installs h as the scanner's ErrorHandler:
+// h is called, in addition to being recorded in Err(), for every error encountered while scanning.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(s *Scanner) { s.errHandler = h }
+}
 
-	// invalid src (not illegal tokens)
-	errs []Error
+// WithMode sets the scanner's Mode flags.
+func WithMode(mode Mode) Option {
+	return func(s *Scanner) { s.mode = mode }
 }
 
-// New returns a ready-to-use scanner.
-func New(file string, src []byte) *Scanner {
+// New returns a ready-to-use scanner for src, whose positions are resolved
+// through file. file must have been obtained from the token.FileSet the
+// caller intends to resolve the returned positions with, typically via
+// FileSet.AddFile(name, len(src)).
+func New(file *token.File, src []byte, opts ...Option) *Scanner {
 	s := &Scanner{
-		filename: file,
-		src:      src,
+		file: file,
+		src:  src,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	s.next()
 	return s
 }
 
+// ErrorCount returns the number of errors encountered so far.
+func (s *Scanner) ErrorCount() int { return len(s.errs) }
+
+// Err returns the errors encountered so far, sorted by position.
+func (s *Scanner) Err() ErrorList {
+	s.errs.Sort()
+	return s.errs
+}
+
+// error records an error at the given byte offset and notifies the error handler, if any.
+func (s *Scanner) error(offset int, msg string) {
+	pos := s.file.Position(offset)
+	s.errs.Add(pos, msg)
+	if s.errHandler != nil {
+		s.errHandler(pos, msg)
+	}
+}
+
 // Scan scans the next token and returns it with its position and literal string if any.
 // The source ends with the token.EOF token.
 //
 // The position points to the beginning of the token.
 //
 // All tokens have a literal string except token.EOF.
-func (s *Scanner) Scan() (pos Position, tok token.Token, lit string) {
+func (s *Scanner) Scan() (pos token.Pos, tok token.Token, lit string) {
+	if s.buffered {
+		s.buffered = false
+		pos, tok, lit = s.bufPos, s.bufTok, s.bufLit
+	} else {
+		pos, tok, lit = s.scanFiltered()
+	}
+	s.lastPos, s.lastTok, s.lastLit = pos, tok, lit
+	return
+}
+
+// Peek returns the next token without consuming it.
+// The following call to Scan or Peek returns the same token again.
+func (s *Scanner) Peek() (pos token.Pos, tok token.Token, lit string) {
+	if !s.buffered {
+		s.bufPos, s.bufTok, s.bufLit = s.scanFiltered()
+		s.buffered = true
+	}
+	return s.bufPos, s.bufTok, s.bufLit
+}
+
+// Unread pushes the token last returned by Scan back onto the lookahead buffer,
+// so the next call to Scan or Peek returns it again.
+// Calling Unread more than once in a row, or before any call to Scan, is invalid.
+func (s *Scanner) Unread() {
+	s.bufPos, s.bufTok, s.bufLit = s.lastPos, s.lastTok, s.lastLit
+	s.buffered = true
+}
+
+// scanFiltered scans tokens until one survives the scanner's Mode:
+// by default comments are skipped (ScanComments brings them back)
+// and ILLEGAL tokens are skipped only if SkipIllegal is set.
+func (s *Scanner) scanFiltered() (pos token.Pos, tok token.Token, lit string) {
+	for {
+		pos, tok, lit = s.scan()
+		if tok == token.COMMENT && s.mode&ScanComments == 0 {
+			continue
+		}
+		if tok == token.ILLEGAL && s.mode&SkipIllegal != 0 {
+			continue
+		}
+		return
+	}
+}
+
+// scan scans and returns the next token from the input, ignoring the lookahead buffer.
+func (s *Scanner) scan() (pos token.Pos, tok token.Token, lit string) {
 	s.skipSpace()
-	pos = Position{s.filename, s.tokLine, s.tokCol}
+	pos = s.file.Pos(s.start)
 
 	switch current := s.current; {
 
@@ -82,20 +199,42 @@ func (s *Scanner) Scan() (pos Position, tok token.Token, lit string) {
 		} else {
 			tok = token.ILLEGAL
 			lit = "."
+			s.error(s.start, "unexpected character after '.'")
 		}
 
 	case startComment(current):
 		tok, lit = s.comment()
 
+	case current == '"':
+		tok, lit = s.scanString()
+
+	case current == '`':
+		tok, lit = s.scanRawString()
+
+	case current == '\'':
+		tok, lit = s.scanRune()
+
+	case current == '%':
+		tok, lit = s.scanFormat()
+
+	case current == utf8.RuneError && s.w == 1:
+		// invalid UTF-8 encoding; next() already recorded the error.
+		// Report it as its own ILLEGAL token instead of letting it leak
+		// into whatever token comes next.
+		s.next()
+		tok = token.ILLEGAL
+		lit = s.literal()
+		s.moveToken()
+
 	// simple tokens
 	default:
 		switch current {
 		case ',':
 			tok = token.COMMA
 		case ';':
-			tok = token.SEMICOL
+			tok = token.SEMICOLON
 		case ':':
-			tok = token.COLUMN
+			tok = token.COLON
 		case '{':
 			tok = token.LEFTDELIM
 		case '}':
@@ -117,24 +256,28 @@ func (s *Scanner) Scan() (pos Position, tok token.Token, lit string) {
 
 		// move to the position after the token
 		// i.e. position of the next token (or whitespace)
+		start := s.start
 		s.next()
 
 		lit = s.literal()
 		s.moveToken()
+
+		if tok == token.ILLEGAL {
+			s.error(start, fmt.Sprintf("unexpected character %q", current))
+		}
 	}
 
 	return
 }
 
-// next moves the scanner to the next rune.
+// next moves the scanner to the next rune, recording the start of a new line
+// with s.file as soon as it steps past a '\n'.
 // The next rune may be utf8.RuneError, which means that encoding is invalid.
 func (s *Scanner) next() {
-	// move to the next rune
+	sawNewline := s.current == '\n'
 	s.pos += s.w
-	s.col += s.w
-	if s.current == '\n' {
-		s.line++
-		s.col = 0
+	if sawNewline {
+		s.file.AddLine(s.pos)
 	}
 
 	if s.pos >= len(s.src) {
@@ -148,12 +291,12 @@ func (s *Scanner) next() {
 	s.current, s.w = rune(s.src[s.pos]), 1
 	if s.current >= utf8.RuneSelf {
 		// not ASCII
-		s.current, s.w = utf8.DecodeLastRune(s.src[s.pos:])
+		s.current, s.w = utf8.DecodeRune(s.src[s.pos:])
 		if s.current == utf8.RuneError {
 			// input was not empty
 			// so it's an invalid UTF8-encoding (and width is 1)
 			// collect error and continue
-			s.errs = append(s.errs, Error{Position{s.filename, s.line, s.col}})
+			s.error(s.pos, "invalid UTF-8 encoding")
 		}
 	}
 }
@@ -161,8 +304,6 @@ func (s *Scanner) next() {
 // moveToken moves the token position to the current position.
 func (s *Scanner) moveToken() {
 	s.start = s.pos
-	s.tokLine = s.line
-	s.tokCol = s.col
 }
 
 func (s *Scanner) skipSpace() {
@@ -181,6 +322,8 @@ func (s *Scanner) literal() string {
 // scan a comment assuming the current rune is '/'.
 // So call it just after a call to startComment has returned true.
 func (s *Scanner) comment() (tok token.Token, lit string) {
+	start := s.start
+
 	// first rune is '/'
 	s.next()
 
@@ -213,12 +356,13 @@ func (s *Scanner) comment() (tok token.Token, lit string) {
 		tok = token.ILLEGAL
 		lit = s.literal()
 		s.moveToken()
+		s.error(start, fmt.Sprintf("unexpected character %q after '/'", s.current))
 		return
 	}
 
 	// at this point we know the comment left delimiter
 	// and the scanner points to the first rune after
-	if start := string(s.src[s.start:s.pos]); start == "//" {
+	if delim := string(s.src[start:s.pos]); delim == "//" {
 		// comment until the end of line
 		// loop until we find EOL or EOF
 		for s.current != eof && s.current != '\n' && s.current != '\r' {
@@ -244,6 +388,7 @@ func (s *Scanner) comment() (tok token.Token, lit string) {
 		if s.current == eof {
 			tok = token.ILLEGAL
 			lit = s.literal()
+			s.error(start, "unclosed comment")
 			return
 		}
 		// current rune is '*'
@@ -260,6 +405,125 @@ func (s *Scanner) comment() (tok token.Token, lit string) {
 
 }
 
+// scanString scans a double-quoted string literal, assuming the current rune is '"'.
+// A backslash escapes the rune that follows it, so \" does not end the string;
+// escapes are not validated here, decoding them (\" \\ \n \t \r and the others)
+// is left to token.Unquote. An unterminated string (one that hits
+// EOL or EOF before the closing quote) produces an ILLEGAL token,
+// the same way an unclosed comment does.
+func (s *Scanner) scanString() (tok token.Token, lit string) {
+	start := s.start
+	s.next() // consume the opening '"'
+
+	for {
+		switch s.current {
+		case '"':
+			s.next() // consume the closing quote
+			tok = token.STRING
+			lit = s.literal()
+			s.moveToken()
+			return
+
+		case eof, '\n':
+			tok = token.ILLEGAL
+			lit = s.literal()
+			s.moveToken()
+			s.error(start, "unterminated string literal")
+			return
+
+		case '\\':
+			s.next()
+			switch s.current {
+			case '"', '\\', 'n', 't', 'r':
+				s.next()
+			default:
+				// unknown escape: keep it as-is, let the caller decide whether it is valid
+				s.next()
+			}
+
+		default:
+			s.next()
+		}
+	}
+}
+
+// scanRawString scans a backtick-delimited raw string literal, assuming the
+// current rune is '`'. No escapes are recognized: the literal runs until the
+// next backtick. An unterminated raw string (one that hits EOF before the
+// closing backtick) produces an ILLEGAL token.
+func (s *Scanner) scanRawString() (tok token.Token, lit string) {
+	start := s.start
+	s.next() // consume the opening '`'
+
+	for s.current != '`' && s.current != eof {
+		s.next()
+	}
+
+	if s.current == eof {
+		tok = token.ILLEGAL
+		lit = s.literal()
+		s.moveToken()
+		s.error(start, "unterminated raw string literal")
+		return
+	}
+
+	s.next() // consume the closing '`'
+	tok = token.STRING
+	lit = s.literal()
+	s.moveToken()
+	return
+}
+
+// scanRune scans a single-quoted char literal such as 'a', '\n', '\x41' or
+// 'é', assuming the current rune is a single quote. scanRune only finds
+// the closing quote; whether the literal decodes to exactly one character is
+// left entirely to token.Unquote, the same single source of truth used for
+// STRING literals. A literal that hits EOL or EOF before the closing quote
+// produces an ILLEGAL token.
+func (s *Scanner) scanRune() (tok token.Token, lit string) {
+	start := s.start
+	s.next() // consume the opening '\''
+
+	for s.current != '\'' && s.current != eof && s.current != '\n' {
+		if s.current == '\\' {
+			s.next()
+		}
+		s.next()
+	}
+
+	if s.current != '\'' {
+		tok = token.ILLEGAL
+		lit = s.literal()
+		s.moveToken()
+		s.error(start, "unterminated char literal")
+		return
+	}
+	s.next() // consume the closing quote
+
+	tok = token.CHAR
+	lit = s.literal()
+	s.moveToken()
+	if _, err := token.Unquote(lit); err != nil {
+		tok = token.ILLEGAL
+		s.error(start, err.Error())
+	}
+	return
+}
+
+// scanFormat scans a '%'-prefixed format specifier such as %B or %B1.16.1,
+// assuming the current rune is '%'.
+func (s *Scanner) scanFormat() (tok token.Token, lit string) {
+	s.next() // consume the '%'
+
+	for isAlphanumeric(s.current) || s.current == '.' {
+		s.next()
+	}
+	tok = token.FORMAT
+	lit = s.literal()
+	s.moveToken()
+	return
+}
+
 // only 0-9
 func isDigit(r rune) bool { return '0' <= r && r <= '9' }
 
@@ -6,6 +6,15 @@ import (
 	"github.com/batiazinga/hdl/iohdl/internal/token"
 )
 
+// newScanner builds a scanner over src, registering it as "filename" in a
+// fresh FileSet so the returned positions can be resolved with fset.Position.
+// The scanner records each line start itself as it scans past it.
+func newScanner(src string, opts ...Option) (*Scanner, *token.FileSet) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("filename", len(src))
+	return New(file, []byte(src), opts...), fset
+}
+
 // TestScanner checks that the scanner returns the expected tokens in the expected order.
 func TestScanner(t *testing.T) {
 
@@ -153,6 +162,99 @@ func TestScanner(t *testing.T) {
 				token.ILLEGAL,
 			},
 		},
+
+		// strings and format specifiers
+		{
+			"simple string",
+			`"And.hdl"`,
+			[]token.Token{token.STRING},
+		},
+		{
+			"string with escapes",
+			`"line1\nline2\t\"quoted\""`,
+			[]token.Token{token.STRING},
+		},
+		{
+			"two strings",
+			`"a" "b"`,
+			[]token.Token{token.STRING, token.STRING},
+		},
+		{
+			"unterminated string at eol",
+			"\"abc\ndef\"",
+			// the unterminated "abc is illegal, def is a valid identifier,
+			// and the orphaned closing quote starts its own unterminated string
+			[]token.Token{token.ILLEGAL, token.IDENT, token.ILLEGAL},
+		},
+		{
+			"unterminated string at eof",
+			`"abc`,
+			[]token.Token{token.ILLEGAL},
+		},
+		{
+			"format specifier",
+			"%B1.16.1",
+			[]token.Token{token.FORMAT},
+		},
+		{
+			"format specifier without width",
+			"%D",
+			[]token.Token{token.FORMAT},
+		},
+
+		// raw strings and char literals
+		{
+			"raw string",
+			"`a\\b`",
+			[]token.Token{token.STRING},
+		},
+		{
+			"unterminated raw string",
+			"`a\\b",
+			[]token.Token{token.ILLEGAL},
+		},
+		{
+			"simple char",
+			`'a'`,
+			[]token.Token{token.CHAR},
+		},
+		{
+			"escaped char",
+			`'\n'`,
+			[]token.Token{token.CHAR},
+		},
+		{
+			"char with hex escape",
+			`'\x41'`,
+			[]token.Token{token.CHAR},
+		},
+		{
+			"char with unicode escape",
+			`'\u00e9'`,
+			[]token.Token{token.CHAR},
+		},
+		{
+			"unterminated char at eol",
+			"'a\nb'",
+			// the unterminated 'a is illegal, b is a valid identifier,
+			// and the orphaned closing quote starts its own unterminated char literal
+			[]token.Token{token.ILLEGAL, token.IDENT, token.ILLEGAL},
+		},
+		{
+			"unterminated char at eof",
+			`'a`,
+			[]token.Token{token.ILLEGAL},
+		},
+		{
+			"char literal with too many characters",
+			`'ab'`,
+			[]token.Token{token.ILLEGAL},
+		},
+		{
+			"empty char literal",
+			`''`,
+			[]token.Token{token.ILLEGAL},
+		},
 	}
 
 	// run tests
@@ -161,7 +263,9 @@ func TestScanner(t *testing.T) {
 			testcase.label,
 			func(t *testing.T) {
 				// build lexer for this test
-				s := New("filename", []byte(testcase.src))
+				// ScanComments is set so existing comment-related cases keep seeing token.COMMENT;
+				// the default (no mode) behavior is covered separately by TestModeDefault.
+				s, _ := newScanner(testcase.src, WithMode(ScanComments))
 
 				// store all tokens in a slice (except EOF)
 				var tokens []token.Token
@@ -189,6 +293,223 @@ func TestScanner(t *testing.T) {
 	}
 }
 
+// TestOffset checks that the scanned position resolves to the byte offset of
+// a token in the source.
+func TestOffset(t *testing.T) {
+	s, fset := newScanner("CHIP And {")
+
+	testcases := []int{0, 5, 9}
+	for _, want := range testcases {
+		pos, _, _ := s.Scan()
+		if got := fset.Position(pos).Offset(); got != want {
+			t.Errorf("unexpected offset: %d instead of %d", got, want)
+		}
+	}
+}
+
+// TestErrorHandler checks that the installed ErrorHandler is called for every
+// illegal token, and that the errors are also available through Err/ErrorCount.
+func TestErrorHandler(t *testing.T) {
+	var reported []string
+	handler := func(pos token.Position, msg string) {
+		reported = append(reported, msg)
+	}
+
+	s, _ := newScanner("CHIP * And", WithErrorHandler(handler))
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	if len(reported) != 1 {
+		t.Fatalf("unexpected number of reported errors: %d instead of 1 (%v)", len(reported), reported)
+	}
+	if got, want := s.ErrorCount(), 1; got != want {
+		t.Errorf("unexpected ErrorCount: %d instead of %d", got, want)
+	}
+	if got, want := len(s.Err()), 1; got != want {
+		t.Errorf("unexpected Err() length: %d instead of %d", got, want)
+	}
+}
+
+// TestPeek checks that Peek returns the next token without consuming it
+// and that the following Scan returns that same token.
+func TestPeek(t *testing.T) {
+	s, _ := newScanner("CHIP And {")
+
+	peekPos, peekTok, peekLit := s.Peek()
+	if peekTok != token.DECL {
+		t.Fatalf("unexpected peeked token: %s instead of %s", peekTok, token.DECL)
+	}
+
+	// peeking again must return the exact same result
+	pos, tok, lit := s.Peek()
+	if pos != peekPos || tok != peekTok || lit != peekLit {
+		t.Fatalf("peek is not idempotent: (%v,%s,%q) instead of (%v,%s,%q)", pos, tok, lit, peekPos, peekTok, peekLit)
+	}
+
+	// Scan must return the peeked token
+	pos, tok, lit = s.Scan()
+	if pos != peekPos || tok != peekTok || lit != peekLit {
+		t.Fatalf("scan after peek returned (%v,%s,%q) instead of the peeked (%v,%s,%q)", pos, tok, lit, peekPos, peekTok, peekLit)
+	}
+
+	// scanning continues normally afterwards
+	_, tok, _ = s.Scan()
+	if tok != token.IDENT {
+		t.Fatalf("unexpected token after peek: %s instead of %s", tok, token.IDENT)
+	}
+}
+
+// TestModeDefault checks that, without any mode flags, comments are skipped
+// and illegal tokens are still returned.
+func TestModeDefault(t *testing.T) {
+	s, _ := newScanner("CHIP // comment\n* And")
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	want := []token.Token{token.DECL, token.ILLEGAL, token.IDENT}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected number of tokens: %d instead of %d\n  %v\n  %v", len(tokens), len(want), tokens, want)
+	}
+	for i := range tokens {
+		if tokens[i] != want[i] {
+			t.Errorf("unexpected %d-th token: %s instead of %s", i, tokens[i], want[i])
+		}
+	}
+}
+
+// TestModeScanComments checks that WithMode(ScanComments) makes Scan return comments.
+func TestModeScanComments(t *testing.T) {
+	s, _ := newScanner("CHIP // comment\nAnd", WithMode(ScanComments))
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	want := []token.Token{token.DECL, token.COMMENT, token.IDENT}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected number of tokens: %d instead of %d\n  %v\n  %v", len(tokens), len(want), tokens, want)
+	}
+	for i := range tokens {
+		if tokens[i] != want[i] {
+			t.Errorf("unexpected %d-th token: %s instead of %s", i, tokens[i], want[i])
+		}
+	}
+}
+
+// TestModeSkipIllegal checks that WithMode(SkipIllegal) makes Scan silently skip ILLEGAL tokens.
+func TestModeSkipIllegal(t *testing.T) {
+	s, _ := newScanner("CHIP * And", WithMode(SkipIllegal))
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	want := []token.Token{token.DECL, token.IDENT}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected number of tokens: %d instead of %d\n  %v\n  %v", len(tokens), len(want), tokens, want)
+	}
+	for i := range tokens {
+		if tokens[i] != want[i] {
+			t.Errorf("unexpected %d-th token: %s instead of %s", i, tokens[i], want[i])
+		}
+	}
+}
+
+// TestUnread checks that Unread pushes the last scanned token back
+// so the next Scan returns it again.
+func TestUnread(t *testing.T) {
+	s, _ := newScanner("CHIP And {")
+
+	pos1, tok1, lit1 := s.Scan()
+	if tok1 != token.DECL {
+		t.Fatalf("unexpected token: %s instead of %s", tok1, token.DECL)
+	}
+
+	s.Unread()
+
+	pos2, tok2, lit2 := s.Scan()
+	if pos2 != pos1 || tok2 != tok1 || lit2 != lit1 {
+		t.Fatalf("scan after unread returned (%v,%s,%q) instead of (%v,%s,%q)", pos2, tok2, lit2, pos1, tok1, lit1)
+	}
+
+	// scanning continues normally afterwards
+	_, tok, _ := s.Scan()
+	if tok != token.IDENT {
+		t.Fatalf("unexpected token after unread: %s instead of %s", tok, token.IDENT)
+	}
+}
+
+// TestInvalidUTF8 checks that an invalid UTF-8 byte produces its own ILLEGAL
+// token and that scanning resumes normally right after it.
+func TestInvalidUTF8(t *testing.T) {
+	s, _ := newScanner("CHIP \xff And")
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	want := []token.Token{token.DECL, token.ILLEGAL, token.IDENT}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected number of tokens: %d instead of %d\n  %v\n  %v", len(tokens), len(want), tokens, want)
+	}
+	for i := range tokens {
+		if tokens[i] != want[i] {
+			t.Errorf("unexpected %d-th token: %s instead of %s", i, tokens[i], want[i])
+		}
+	}
+
+	if got, want := s.ErrorCount(), 1; got != want {
+		t.Errorf("unexpected ErrorCount: %d instead of %d", got, want)
+	}
+}
+
+// TestMultiByteRune checks that a multi-byte rune following other text
+// is decoded as the rune at the current position, not the last rune of
+// the remaining source (a regression test for a DecodeRune/DecodeLastRune mixup).
+func TestMultiByteRune(t *testing.T) {
+	s, _ := newScanner("γθιπ ω")
+
+	var tokens []token.Token
+	_, tok, _ := s.Scan()
+	for tok != token.EOF {
+		tokens = append(tokens, tok)
+		_, tok, _ = s.Scan()
+	}
+
+	want := []token.Token{token.IDENT, token.IDENT}
+	if len(tokens) != len(want) {
+		t.Fatalf("unexpected number of tokens: %d instead of %d\n  %v\n  %v", len(tokens), len(want), tokens, want)
+	}
+	for i := range tokens {
+		if tokens[i] != want[i] {
+			t.Errorf("unexpected %d-th token: %s instead of %s", i, tokens[i], want[i])
+		}
+	}
+}
+
+// TestPosition checks that token positions, once resolved through the
+// FileSet the scanner was built with, report the expected line and column -
+// in particular that line numbers advance as the scanner itself crosses each
+// '\n', without any separate prescan of the source.
 func TestPosition(t *testing.T) {
 	type position struct {
 		line, column int
@@ -242,13 +563,14 @@ func TestPosition(t *testing.T) {
 		t.Run(
 			testcase.label,
 			func(t *testing.T) {
-				s := New("test.hdl", []byte(testcase.src))
+				s, fset := newScanner(testcase.src)
 
 				// store all positions in a slice (except for EOF)
 				var positions []position
 				pos, tok, _ := s.Scan()
 				for tok != token.EOF {
-					positions = append(positions, position{pos.Line(), pos.Column()})
+					resolved := fset.Position(pos)
+					positions = append(positions, position{resolved.Line(), resolved.Column()})
 					pos, tok, _ = s.Scan()
 				}
 
@@ -0,0 +1,107 @@
+package token
+
+import "sort"
+
+// File describes a single source file registered in a FileSet.
+// It records the offset of every line start as the scanner discovers them,
+// so byte offsets can later be resolved to a line and column.
+type File struct {
+	name string
+	base int // Pos of the first byte of this file in the owning FileSet
+	size int // size of the file in bytes
+
+	// lines[i] is the offset of the start of the (i+1)-th line.
+	// lines[0] is always 0.
+	lines []int
+}
+
+// Name returns the name of the file, e.g. the path it was read from.
+func (f *File) Name() string { return f.name }
+
+// Base returns the Pos of the first byte of the file within its FileSet.
+func (f *File) Base() int { return f.base }
+
+// Size returns the size of the file in bytes.
+func (f *File) Size() int { return f.size }
+
+// AddLine records the offset of the start of a new line.
+// It is called by the scanner every time it sees a '\n'.
+// Calls with a non-increasing or out-of-range offset are ignored.
+func (f *File) AddLine(offset int) {
+	if n := len(f.lines); (n == 0 || f.lines[n-1] < offset) && offset <= f.size {
+		f.lines = append(f.lines, offset)
+	}
+}
+
+// Pos returns the Pos value for the given byte offset within this file.
+func (f *File) Pos(offset int) Pos { return Pos(f.base + offset) }
+
+// LineStart returns the byte offset of the start of the given 1-indexed line.
+// It returns -1 if line is out of the range of lines recorded so far.
+func (f *File) LineStart(line int) int {
+	if line < 1 || line > len(f.lines) {
+		return -1
+	}
+	return f.lines[line-1]
+}
+
+// Position resolves a byte offset within this file into a full Position.
+func (f *File) Position(offset int) Position {
+	line, column := f.lineAndColumn(offset)
+	return Position{filename: f.name, offset: offset, line: line - 1, column: column - 1}
+}
+
+// lineAndColumn finds the 1-indexed line and column of offset
+// by binary-searching the recorded line-start offsets.
+func (f *File) lineAndColumn(offset int) (line, column int) {
+	i := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset }) - 1
+	if i < 0 {
+		i = 0
+	}
+	return i + 1, offset - f.lines[i] + 1
+}
+
+// FileSet owns a set of files and lets positions from different files
+// be represented with a single, comparable Pos type.
+type FileSet struct {
+	base  int
+	files []*File
+}
+
+// NewFileSet creates a new, empty FileSet.
+func NewFileSet() *FileSet {
+	return &FileSet{base: 1}
+}
+
+// AddFile registers a new file of the given size and returns it.
+// The scanner should call File.AddLine on the returned File as it scans the file.
+func (s *FileSet) AddFile(name string, size int) *File {
+	f := &File{name: name, base: s.base, size: size, lines: []int{0}}
+	s.base += size + 1
+	s.files = append(s.files, f)
+	return f
+}
+
+// File returns the file that p belongs to, or nil if p does not belong to any file of s.
+func (s *FileSet) File(p Pos) *File {
+	offset := int(p)
+	i := sort.Search(len(s.files), func(i int) bool { return s.files[i].base > offset }) - 1
+	if i < 0 {
+		return nil
+	}
+	f := s.files[i]
+	if offset > f.base+f.size {
+		return nil
+	}
+	return f
+}
+
+// Position resolves p into a full Position, using the file it belongs to.
+// It returns the zero Position if p does not belong to any file of s.
+func (s *FileSet) Position(p Pos) Position {
+	f := s.File(p)
+	if f == nil {
+		return Position{}
+	}
+	return f.Position(int(p) - f.base)
+}
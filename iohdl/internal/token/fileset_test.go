@@ -0,0 +1,42 @@
+package token
+
+import "testing"
+
+func TestFileSetPosition(t *testing.T) {
+	fset := NewFileSet()
+
+	f1 := fset.AddFile("a.hdl", 20)
+	f1.AddLine(5)
+	f1.AddLine(12)
+
+	f2 := fset.AddFile("b.hdl", 10)
+	f2.AddLine(4)
+
+	testcases := []struct {
+		label        string
+		pos          Pos
+		filename     string
+		line, column int
+	}{
+		{"a.hdl first line", f1.Pos(0), "a.hdl", 1, 1},
+		{"a.hdl second line", f1.Pos(7), "a.hdl", 2, 3},
+		{"a.hdl third line", f1.Pos(15), "a.hdl", 3, 4},
+		{"b.hdl first line", f2.Pos(0), "b.hdl", 1, 1},
+		{"b.hdl second line", f2.Pos(6), "b.hdl", 2, 3},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			pos := fset.Position(tc.pos)
+			if pos.Filename() != tc.filename {
+				t.Errorf("unexpected filename: %s instead of %s", pos.Filename(), tc.filename)
+			}
+			if pos.Line() != tc.line {
+				t.Errorf("unexpected line: %d instead of %d", pos.Line(), tc.line)
+			}
+			if pos.Column() != tc.column {
+				t.Errorf("unexpected column: %d instead of %d", pos.Column(), tc.column)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Unquote decodes the value represented by a STRING or CHAR literal as
+// scanned by the scanner, delimiters included. Backtick-delimited raw
+// strings are returned unmodified, without their delimiters; double-quoted
+// strings and single-quoted chars have their escapes decoded.
+//
+// Recognized escapes are \n \t \r \" \' \\ \xNN and \uNNNN. Both \xNN (two hex
+// digits) and \uNNNN (four hex digits) decode to the single rune with that code point.
+func Unquote(lit string) (string, error) {
+	if len(lit) < 2 {
+		return "", fmt.Errorf("token: invalid literal %q", lit)
+	}
+
+	quote := lit[0]
+	if quote != '"' && quote != '`' && quote != '\'' {
+		return "", fmt.Errorf("token: invalid literal %q", lit)
+	}
+	if lit[len(lit)-1] != quote {
+		return "", fmt.Errorf("token: unterminated literal %q", lit)
+	}
+	body := lit[1 : len(lit)-1]
+
+	if quote == '`' {
+		return body, nil
+	}
+
+	var out []rune
+	for i := 0; i < len(body); {
+		if body[i] != '\\' {
+			r, w := utf8.DecodeRuneInString(body[i:])
+			out = append(out, r)
+			i += w
+			continue
+		}
+
+		if i+1 >= len(body) {
+			return "", fmt.Errorf("token: invalid escape at end of literal %q", lit)
+		}
+		switch body[i+1] {
+		case 'n':
+			out = append(out, '\n')
+			i += 2
+		case 't':
+			out = append(out, '\t')
+			i += 2
+		case 'r':
+			out = append(out, '\r')
+			i += 2
+		case '"':
+			out = append(out, '"')
+			i += 2
+		case '\'':
+			out = append(out, '\'')
+			i += 2
+		case '\\':
+			out = append(out, '\\')
+			i += 2
+		case 'x':
+			if i+4 > len(body) {
+				return "", fmt.Errorf("token: invalid \\x escape in literal %q", lit)
+			}
+			n, err := strconv.ParseUint(body[i+2:i+4], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("token: invalid \\x escape in literal %q", lit)
+			}
+			out = append(out, rune(n))
+			i += 4
+		case 'u':
+			if i+6 > len(body) {
+				return "", fmt.Errorf("token: invalid \\u escape in literal %q", lit)
+			}
+			n, err := strconv.ParseUint(body[i+2:i+6], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("token: invalid \\u escape in literal %q", lit)
+			}
+			out = append(out, rune(n))
+			i += 6
+		default:
+			return "", fmt.Errorf("token: unknown escape %q in literal %q", body[i:i+2], lit)
+		}
+	}
+
+	s := string(out)
+	if quote == '\'' && len(out) != 1 {
+		return "", fmt.Errorf("token: char literal %q does not contain exactly one character", lit)
+	}
+	return s, nil
+}
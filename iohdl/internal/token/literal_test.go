@@ -0,0 +1,57 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+func TestUnquote(t *testing.T) {
+	testcases := []struct {
+		label string
+		lit   string
+		want  string
+	}{
+		{"simple string", `"And.hdl"`, "And.hdl"},
+		{"string with escapes", `"line1\nline2\t\"quoted\""`, "line1\nline2\t\"quoted\""},
+		{"string with hex escape", `"\x41"`, "A"},
+		{"string with unicode escape", `"\u00e9"`, "é"},
+		{"raw string", "`a\\nb`", `a\nb`},
+		{"simple char", `'a'`, "a"},
+		{"newline char", `'\n'`, "\n"},
+		{"char with hex escape", `'\x41'`, "A"},
+		{"char with unicode escape", `'\u00e9'`, "é"},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.label, func(t *testing.T) {
+			got, err := token.Unquote(testcase.lit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != testcase.want {
+				t.Errorf("unexpected value: %q instead of %q", got, testcase.want)
+			}
+		})
+	}
+}
+
+func TestUnquoteErrors(t *testing.T) {
+	testcases := []struct {
+		label string
+		lit   string
+	}{
+		{"unterminated", `"abc`},
+		{"unknown escape", `"\q"`},
+		{"multi-char literal", `'ab'`},
+		{"empty char literal", `''`},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.label, func(t *testing.T) {
+			if _, err := token.Unquote(testcase.lit); err == nil {
+				t.Errorf("expected an error for %q, got none", testcase.lit)
+			}
+		})
+	}
+}
@@ -2,14 +2,43 @@ package token
 
 import "fmt"
 
-// Position is a position in an input file.
+// Pos is a compact handle to a position held by a FileSet.
+// It is comparable and orderable like a plain offset,
+// and is resolved to a full Position through FileSet.Position.
+//
+// The zero value is NoPos: it carries no position information.
+type Pos int
+
+// NoPos is the zero value for Pos.
+// There is no file and line information associated with it,
+// and NoPos.IsValid() is false.
+const NoPos Pos = 0
+
+// IsValid reports whether the position is valid, i.e. not NoPos.
+func (p Pos) IsValid() bool { return p != NoPos }
+
+// Position describes a resolved source position:
+// the file it belongs to, its byte offset in that file,
+// and the corresponding line and column.
 type Position struct {
+	filename     string
+	offset       int
 	line, column int
 }
 
-// NewPosition returns a position pointing to (line, column).
+// NewPosition returns a position pointing to (line, column) with no filename or offset.
 // Parameters line and column are 0-indexed.
-func NewPosition(line, column int) Position { return Position{line, column} }
+//
+// It is mostly useful for tests; positions obtained by scanning real source
+// should go through a FileSet so Filename and Offset are populated too.
+func NewPosition(line, column int) Position { return Position{line: line, column: column} }
+
+// Filename returns the name of the file this position belongs to.
+// It is empty if the position was not resolved through a FileSet.
+func (p Position) Filename() string { return p.filename }
+
+// Offset returns the byte offset of the position in its file, starting at 0.
+func (p Position) Offset() int { return p.offset }
 
 // Line returns the line number.
 // The first line has index 1.
@@ -20,7 +49,10 @@ func (p Position) Line() int { return p.line + 1 }
 func (p Position) Column() int { return p.column + 1 }
 
 func (p Position) String() string {
-	return fmt.Sprintf("line %d column %d", p.Line(), p.Column())
+	if p.filename == "" {
+		return fmt.Sprintf("line %d column %d", p.Line(), p.Column())
+	}
+	return fmt.Sprintf("%s line %d column %d", p.filename, p.Line(), p.Column())
 }
 
 // Less returns true if p is strictly less than q.
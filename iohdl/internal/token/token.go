@@ -17,6 +17,9 @@ const (
 	NUMBER // 123
 	TRUE   // true
 	FALSE  // false
+	STRING // "a string", `a raw string`
+	CHAR   // 'a', '\n'
+	FORMAT // %B1.16.1, used by test scripts and cmp files
 
 	// Delimiters and separators
 	COMMA      // ,
@@ -48,6 +51,9 @@ var tokenStrings = [...]string{
 	NUMBER: "NUMBER",
 	TRUE:   "true",
 	FALSE:  "false",
+	STRING: "STRING",
+	CHAR:   "CHAR",
+	FORMAT: "FORMAT",
 
 	COMMA:      ",",
 	SEMICOLON:  ";",
@@ -0,0 +1,339 @@
+// Package parser implements a parser for hdl source files.
+// It turns source text into a description.Chip, the way go/parser
+// turns Go source into a go/ast.File.
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/batiazinga/hdl/iohdl/internal/description"
+	"github.com/batiazinga/hdl/iohdl/internal/scanner"
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+// Mode controls optional parser functionality.
+type Mode uint
+
+const (
+	// ParseComments instructs the parser to attach comments to the built chip.
+	// Without it, comments are skipped for speed.
+	ParseComments Mode = 1 << iota
+
+	// DeclarationErrors instructs the parser to fail hard on duplicate pin names.
+	DeclarationErrors
+)
+
+// ParseChip parses the hdl source of a single chip and returns its description.
+//
+// filename is only used for error messages and to register the file in fset;
+// src is the actual source to parse. On a parse error, the returned error is a
+// non-empty scanner.ErrorList, sorted by position.
+func ParseChip(fset *token.FileSet, filename string, src []byte, mode Mode) (description.Chip, error) {
+	p := &parser{
+		fset: fset,
+		scan: scanner.New(fset.AddFile(filename, len(src)), src, scanner.WithMode(scanner.ScanComments)),
+		mode: mode,
+	}
+	p.next()
+
+	chip := p.parseChip()
+	if len(p.errs) > 0 {
+		p.errs.RemoveMultiples()
+		return chip, p.errs
+	}
+	return chip, nil
+}
+
+// ParseDir parses every file of dir matching filter (all ".hdl" files if filter is nil)
+// and returns the chip described by each of them, keyed by chip name.
+func ParseDir(fset *token.FileSet, dir string, filter func(os.FileInfo) bool, mode Mode) (map[string]description.Chip, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	chips := make(map[string]description.Chip)
+	var errs scanner.ErrorList
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if filter != nil {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			if !filter(info) {
+				continue
+			}
+		} else if filepath.Ext(entry.Name()) != ".hdl" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		chip, err := ParseChip(fset, path, src, mode)
+		if err != nil {
+			if list, ok := err.(scanner.ErrorList); ok {
+				errs = append(errs, list...)
+				continue
+			}
+			return nil, err
+		}
+		chips[chip.Name()] = chip
+	}
+
+	if len(errs) > 0 {
+		errs.RemoveMultiples()
+		return chips, errs
+	}
+	return chips, nil
+}
+
+// parser holds the state of a single ParseChip call.
+type parser struct {
+	fset *token.FileSet
+	scan *scanner.Scanner
+	mode Mode
+
+	pos token.Pos
+	tok token.Token
+	lit string
+
+	comments []description.Comment
+	errs     scanner.ErrorList
+
+	// pinNames records every IN/OUT pin name declared so far, so
+	// DeclarationErrors can flag a duplicate. Inputs and outputs share one
+	// namespace: a pin can't be redeclared as both.
+	pinNames map[string]bool
+}
+
+// next advances to the next non-comment token, collecting comments along the way
+// when ParseComments is set.
+func (p *parser) next() {
+	for {
+		pos, tok, lit := p.scan.Scan()
+		p.pos = pos
+		p.tok = tok
+		p.lit = lit
+		if tok != token.COMMENT {
+			return
+		}
+		if p.mode&ParseComments != 0 {
+			end := p.pos + token.Pos(len(lit))
+			p.comments = append(p.comments, description.NewComment(p.pos, end, lit))
+		}
+	}
+}
+
+// errorf records a parse error at the current position.
+// It does not recover by itself: callers that need to skip ahead call sync.
+func (p *parser) errorf(format string, args ...interface{}) {
+	p.errs.Add(p.fset.Position(p.pos), fmt.Sprintf(format, args...))
+}
+
+// errorfAt records a parse error at pos, for checks that happen after the
+// parser has already moved past the token being reported on.
+func (p *parser) errorfAt(pos token.Pos, format string, args ...interface{}) {
+	p.errs.Add(p.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+// declarePin records name as declared at pos and, when DeclarationErrors is
+// set, reports an error if it was already declared: IN and OUT pins share a
+// single namespace, so a chip can't redeclare the same name as either.
+func (p *parser) declarePin(pos token.Pos, name string) {
+	if p.mode&DeclarationErrors == 0 {
+		return
+	}
+	if p.pinNames == nil {
+		p.pinNames = make(map[string]bool)
+	}
+	if p.pinNames[name] {
+		p.errorfAt(pos, "pin %q declared more than once", name)
+		return
+	}
+	p.pinNames[name] = true
+}
+
+// expect reports an error if the current token does not match tok. Either
+// way it advances past the current token, the same way go/parser's expect
+// does, so a single mismatch doesn't leave the parser stuck re-reporting the
+// same token against every expect call downstream. It returns the position
+// of the consumed token.
+func (p *parser) expect(tok token.Token) token.Pos {
+	pos := p.pos
+	if p.tok != tok {
+		p.errorf("expected %s, found %s %q", tok, p.tok, p.lit)
+	}
+	p.next()
+	return pos
+}
+
+// parseChip parses: [comments] CHIP ident '{' inputs outputs parts '}' .
+func (p *parser) parseChip() description.Chip {
+	var b description.ChipBuilder
+
+	start := p.expect(token.DECL)
+	name := p.lit
+	p.expect(token.IDENT)
+	p.expect(token.LEFTDELIM)
+
+	inputs := p.parseInputs()
+	outputs := p.parseOutputs()
+	parts := p.parseParts()
+
+	end := p.expect(token.RIGTDELIM)
+
+	b.Declare(start, end, name)
+	b.DeclareInputs(inputs)
+	b.DeclareOutputs(outputs)
+	for _, part := range parts {
+		b.AppendPart(part)
+	}
+	for _, c := range p.comments {
+		b.AppendComment(c)
+	}
+
+	return b.Build()
+}
+
+// parseInputs parses: 'IN' pin (',' pin)* ';' .
+func (p *parser) parseInputs() description.InputList {
+	if p.tok != token.IN {
+		p.errorf("expected IN declaration, found %s %q", p.tok, p.lit)
+		p.sync()
+		return nil
+	}
+	p.next()
+
+	var inputs description.InputList
+	for {
+		start := p.pos
+		name := p.lit
+		p.expect(token.IDENT)
+		size := p.parseSize()
+		p.declarePin(start, name)
+		inputs = append(inputs, description.NewInput(start, p.pos, name, size))
+
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	p.expect(token.SEMICOLON)
+	return inputs
+}
+
+// parseOutputs parses: 'OUT' pin (',' pin)* ';' .
+func (p *parser) parseOutputs() description.OutputList {
+	if p.tok != token.OUT {
+		p.errorf("expected OUT declaration, found %s %q", p.tok, p.lit)
+		p.sync()
+		return nil
+	}
+	p.next()
+
+	var outputs description.OutputList
+	for {
+		start := p.pos
+		name := p.lit
+		p.expect(token.IDENT)
+		size := p.parseSize()
+		p.declarePin(start, name)
+		outputs = append(outputs, description.NewOutput(start, p.pos, name, size))
+
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+	p.expect(token.SEMICOLON)
+	return outputs
+}
+
+// parseSize parses an optional '[' NUMBER ']' suffix and returns the pin size, 1 if absent.
+func (p *parser) parseSize() int {
+	if p.tok != token.LEFTINDEX {
+		return 1
+	}
+	p.next()
+	size, err := strconv.Atoi(p.lit)
+	if err != nil {
+		p.errorf("expected a pin size, found %q", p.lit)
+	}
+	p.expect(token.NUMBER)
+	p.expect(token.RIGHTINDEX)
+	return size
+}
+
+// parseParts parses: 'PARTS' '{' part* '}' .
+func (p *parser) parseParts() []description.Part {
+	if p.tok != token.PARTS {
+		p.errorf("expected PARTS declaration, found %s %q", p.tok, p.lit)
+		p.sync()
+		return nil
+	}
+	p.next()
+	p.expect(token.LEFTDELIM)
+
+	var parts []description.Part
+	for p.tok == token.IDENT {
+		parts = append(parts, p.parsePart())
+	}
+
+	p.expect(token.RIGTDELIM)
+	return parts
+}
+
+// parsePart parses: ident '(' connection (',' connection)* ')' ';' .
+func (p *parser) parsePart() description.Part {
+	start := p.pos
+	name := p.lit
+	p.expect(token.IDENT)
+	p.expect(token.LEFTPAR)
+
+	var connections []description.Connection
+	for p.tok == token.IDENT {
+		partPin := p.lit
+		p.next()
+		p.expect(token.PIPE)
+		if p.tok != token.IDENT && p.tok != token.TRUE && p.tok != token.FALSE {
+			p.errorf("expected a pin name or a boolean literal, found %s %q", p.tok, p.lit)
+			p.sync()
+			return description.NewPart(start, p.pos, name, connections)
+		}
+		chipPin := p.lit
+		p.next()
+		connections = append(connections, description.NewConnection(partPin, chipPin))
+
+		if p.tok != token.COMMA {
+			break
+		}
+		p.next()
+	}
+
+	p.expect(token.RIGHTPAR)
+	end := p.expect(token.SEMICOLON)
+	return description.NewPart(start, end, name, connections)
+}
+
+// sync advances the parser to the next natural synchronization point
+// (a ';' inside PARTS, a '}' at the end of a block, or the next CHIP keyword),
+// so a single syntax error does not abort the whole parse.
+func (p *parser) sync() {
+	for p.tok != token.EOF {
+		switch p.tok {
+		case token.SEMICOLON, token.RIGTDELIM, token.DECL:
+			return
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,152 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/batiazinga/hdl/iohdl/internal/scanner"
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+	"github.com/batiazinga/hdl/iohdl/parser"
+)
+
+func TestParseChip(t *testing.T) {
+	src := `CHIP And {
+	IN a, b;
+	OUT out;
+
+	PARTS {
+		Nand(a=a, b=b, out=nandOut);
+		Not(in=nandOut, out=out);
+	}
+}`
+
+	fset := token.NewFileSet()
+	chip, err := parser.ParseChip(fset, "And.hdl", []byte(src), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := chip.Name(), "And"; got != want {
+		t.Errorf("unexpected chip name: %q instead of %q", got, want)
+	}
+	if got, want := chip.Inputs().Len(), 2; got != want {
+		t.Errorf("unexpected number of inputs: %d instead of %d", got, want)
+	}
+	if got, want := chip.Outputs().Len(), 1; got != want {
+		t.Errorf("unexpected number of outputs: %d instead of %d", got, want)
+	}
+	if got, want := chip.Parts().Len(), 2; got != want {
+		t.Errorf("unexpected number of parts: %d instead of %d", got, want)
+	}
+	if got, want := chip.Parts().At(0).Name(), "Nand"; got != want {
+		t.Errorf("unexpected first part name: %q instead of %q", got, want)
+	}
+}
+
+func TestParseChipRecoversFromErrors(t *testing.T) {
+	// missing ';' after the IN declaration
+	src := `CHIP Buggy {
+	IN a, b
+	OUT out;
+	PARTS {
+		Not(in=a, out=out);
+	}
+}`
+
+	fset := token.NewFileSet()
+	_, err := parser.ParseChip(fset, "Buggy.hdl", []byte(src), 0)
+	if err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+}
+
+// TestParseChipDeclarationErrors checks that, with DeclarationErrors set,
+// redeclaring a pin name (here the same input declared twice) is reported.
+func TestParseChipDeclarationErrors(t *testing.T) {
+	src := `CHIP Buggy {
+	IN a, a;
+	OUT out;
+	PARTS {
+		Not(in=a, out=out);
+	}
+}`
+
+	fset := token.NewFileSet()
+	_, err := parser.ParseChip(fset, "Buggy.hdl", []byte(src), parser.DeclarationErrors)
+	if err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+}
+
+// TestParseChipWithoutDeclarationErrors checks that the same duplicate pin
+// name is silently accepted when DeclarationErrors is not set.
+func TestParseChipWithoutDeclarationErrors(t *testing.T) {
+	src := `CHIP Buggy {
+	IN a, a;
+	OUT out;
+	PARTS {
+		Not(in=a, out=out);
+	}
+}`
+
+	fset := token.NewFileSet()
+	chip, err := parser.ParseChip(fset, "Buggy.hdl", []byte(src), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := chip.Inputs().Len(), 2; got != want {
+		t.Errorf("unexpected number of inputs: %d instead of %d", got, want)
+	}
+}
+
+// TestParseChipBadChipPinReportsOneError checks that a missing chip pin after
+// '=' inside a part's connection list is reported as a single clean error,
+// instead of being silently accepted as a connection to "," and cascading
+// into an unrelated "expected )" error once parsePart runs off the rails.
+func TestParseChipBadChipPinReportsOneError(t *testing.T) {
+	src := `CHIP Buggy {
+	IN a;
+	OUT out;
+	PARTS {
+		Not(in=, out=out);
+	}
+}`
+
+	fset := token.NewFileSet()
+	_, err := parser.ParseChip(fset, "Buggy.hdl", []byte(src), 0)
+	if err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	if got, want := len(list), 1; got != want {
+		t.Errorf("unexpected number of errors: %d instead of %d (%v)", got, want, err)
+	}
+}
+
+// TestParseChipSinglePinErrorDoesNotCascade checks that a single bad pin name
+// inside IN reports exactly one error, instead of expect repeatedly
+// re-reporting the same unconsumed token against parseSize, the closing
+// ';' and then parseOutputs' own check.
+func TestParseChipSinglePinErrorDoesNotCascade(t *testing.T) {
+	src := `CHIP Buggy {
+	IN *;
+	OUT out;
+	PARTS {
+	}
+}`
+
+	fset := token.NewFileSet()
+	_, err := parser.ParseChip(fset, "Buggy.hdl", []byte(src), 0)
+	if err == nil {
+		t.Fatal("expected a parse error, got nil")
+	}
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	if got, want := len(list), 1; got != want {
+		t.Errorf("unexpected number of errors: %d instead of %d (%v)", got, want, err)
+	}
+}
@@ -0,0 +1,29 @@
+package printer
+
+import "github.com/batiazinga/hdl/iohdl/internal/token"
+
+// Config controls how Fprint renders a chip.
+type Config struct {
+	// UseSpaces indents with TabWidth spaces instead of a tab character.
+	UseSpaces bool
+
+	// TabWidth is the number of spaces used for one indentation level
+	// when UseSpaces is set. It defaults to 4.
+	TabWidth int
+
+	// MaxWidth is the maximum line width before a part's argument list wraps
+	// onto the following lines. A value <= 0 disables wrapping.
+	MaxWidth int
+
+	// NormalizeCase rewrites the true/false literals used as part connections
+	// to their canonical lowercase spelling, regardless of how they were
+	// written in the source (the scanner itself only recognizes the exact
+	// "true"/"false" spelling as keywords, so any other casing reaches here
+	// as a plain pin name).
+	NormalizeCase bool
+
+	// FileSet resolves the positions recorded on c's parts to line numbers,
+	// so Fprint can reproduce the blank lines that separated parts in the
+	// original source. If nil, parts are printed back to back.
+	FileSet *token.FileSet
+}
@@ -0,0 +1,281 @@
+// Package printer implements printing of a description.Chip in canonical hdl format,
+// the counterpart of the scanner/description pair: description records positions,
+// printer turns a description back into readable source.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/batiazinga/hdl/iohdl/internal/description"
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+)
+
+// Fprint pretty-prints chip c to w according to cfg.
+// A nil cfg is equivalent to &Config{}, i.e. the default configuration.
+func Fprint(w io.Writer, c description.Chip, cfg *Config) error {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	p := &printer{w: &bytes.Buffer{}, cfg: cfg}
+
+	groups := groupComments(c)
+
+	p.printComments("", groups.header)
+	p.chipHeader(c)
+	p.printComments(p.indent(), groups.inputs)
+	p.inputs(c.Inputs())
+	p.printComments(p.indent(), groups.outputs)
+	p.outputs(c.Outputs())
+	p.parts(c, groups)
+
+	_, err := w.Write(p.w.Bytes())
+	return err
+}
+
+// printer accumulates the formatted output of a single chip.
+type printer struct {
+	w   *bytes.Buffer
+	cfg *Config
+}
+
+func (p *printer) indent() string {
+	if p.cfg.UseSpaces {
+		n := p.cfg.TabWidth
+		if n <= 0 {
+			n = 4
+		}
+		return fmt.Sprintf("%*s", n, "")
+	}
+	return "\t"
+}
+
+// printComments writes each comment on its own line, indented by indent.
+func (p *printer) printComments(indent string, comments []description.Comment) {
+	for _, comment := range comments {
+		fmt.Fprintf(p.w, "%s%s\n", indent, comment.Literal())
+	}
+}
+
+func (p *printer) chipHeader(c description.Chip) {
+	fmt.Fprintf(p.w, "CHIP %s {\n", c.Name())
+}
+
+// inputs prints the IN declaration: the keyword, one space, then the pins
+// comma-separated on a single, semicolon-terminated line.
+// The IN and OUT pin lists are not column-aligned with each other.
+func (p *printer) inputs(inputs description.InputList) {
+	if inputs.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(p.w, "%sIN ", p.indent())
+	for i := 0; i < inputs.Len(); i++ {
+		if i > 0 {
+			fmt.Fprint(p.w, ", ")
+		}
+		p.writePin(inputs.At(i).Name(), inputs.At(i).Size())
+	}
+	fmt.Fprint(p.w, ";\n")
+}
+
+// outputs prints the OUT declaration, following the same layout as inputs.
+func (p *printer) outputs(outputs description.OutputList) {
+	if outputs.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(p.w, "%sOUT ", p.indent())
+	for i := 0; i < outputs.Len(); i++ {
+		if i > 0 {
+			fmt.Fprint(p.w, ", ")
+		}
+		p.writePin(outputs.At(i).Name(), outputs.At(i).Size())
+	}
+	fmt.Fprint(p.w, ";\n")
+}
+
+func (p *printer) writePin(name string, size int) {
+	if size > 1 {
+		fmt.Fprintf(p.w, "%s[%d]", name, size)
+		return
+	}
+	fmt.Fprint(p.w, name)
+}
+
+// normalizeConnPin rewrites name to "true"/"false" when cfg.NormalizeCase is
+// set and name is some other casing of those literals.
+func (p *printer) normalizeConnPin(name string) string {
+	if p.cfg.NormalizeCase {
+		switch strings.ToLower(name) {
+		case "true":
+			return "true"
+		case "false":
+			return "false"
+		}
+	}
+	return name
+}
+
+func (p *printer) parts(c description.Chip, groups commentGroups) {
+	parts := c.Parts()
+	if parts.Len() == 0 {
+		p.printComments("", groups.end)
+		fmt.Fprint(p.w, "}\n")
+		return
+	}
+
+	fmt.Fprintf(p.w, "%sPARTS {\n", p.indent())
+	var prevEnd token.Pos
+	for i := 0; i < parts.Len(); i++ {
+		part := parts.At(i)
+		if i > 0 {
+			p.blankLine(prevEnd, firstPos(part, groups.parts[i]))
+		}
+		p.printComments(p.indent()+p.indent(), groups.parts[i])
+		p.writePart(part)
+		prevEnd = part.End()
+	}
+	fmt.Fprintf(p.w, "%s}\n", p.indent())
+	p.printComments("", groups.end)
+	fmt.Fprint(p.w, "}\n")
+}
+
+// firstPos returns the position of whichever is printed first for a part:
+// its leading comments if any, otherwise the part itself.
+func firstPos(part description.Part, comments []description.Comment) token.Pos {
+	if len(comments) > 0 {
+		return comments[0].Start()
+	}
+	return part.Start()
+}
+
+// blankLine emits a blank line if from and to, resolved through cfg.FileSet,
+// are separated by more than one source line, preserving the blank-line
+// grouping between parts from the original hdl file. It is a no-op if
+// cfg.FileSet is nil.
+func (p *printer) blankLine(from, to token.Pos) {
+	if p.cfg.FileSet == nil {
+		return
+	}
+	fromLine := p.cfg.FileSet.Position(from).Line()
+	toLine := p.cfg.FileSet.Position(to).Line()
+	if toLine-fromLine > 1 {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// writePart prints a single PARTS line, wrapping its connection list onto
+// continuation lines once it would exceed cfg.MaxWidth.
+func (p *printer) writePart(part description.Part) {
+	conns := make([]string, part.NumConnections())
+	for j := 0; j < part.NumConnections(); j++ {
+		conn := part.Connection(j)
+		conns[j] = fmt.Sprintf("%s=%s", conn.PartPin(), p.normalizeConnPin(conn.ChipPin()))
+	}
+
+	head := fmt.Sprintf("%s%s%s(", p.indent(), p.indent(), part.Name())
+	inline := head + strings.Join(conns, ", ") + ");"
+
+	if p.cfg.MaxWidth <= 0 || len(inline) <= p.cfg.MaxWidth {
+		fmt.Fprintln(p.w, inline)
+		return
+	}
+
+	contIndent := p.indent() + p.indent() + p.indent()
+	fmt.Fprintln(p.w, head)
+	for j, conn := range conns {
+		sep := ","
+		if j == len(conns)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(p.w, "%s%s%s\n", contIndent, conn, sep)
+	}
+	fmt.Fprintf(p.w, "%s%s);\n", p.indent(), p.indent())
+}
+
+// groupKind identifies which declaration a pending comment is attached to.
+type groupKind int
+
+const (
+	groupInputs groupKind = iota
+	groupOutputs
+	groupPart
+	groupEnd
+)
+
+// anchor marks the start position of a declaration a comment can attach to.
+type anchor struct {
+	pos   token.Pos
+	group groupKind
+	index int // part index, meaningful only when group == groupPart
+}
+
+// commentGroups buckets a chip's comments by the declaration that follows
+// them in the source: the chip header, the IN line, the OUT line, a specific
+// part, or the closing brace if nothing follows.
+type commentGroups struct {
+	header, inputs, outputs []description.Comment
+	parts                   [][]description.Comment // parts[i] precedes the i-th part
+	end                     []description.Comment
+}
+
+// groupComments reattaches each of c's comments to the nearest following
+// declaration, using the positions already recorded by the parser -
+// mirroring how go/printer uses a CommentMap.
+func groupComments(c description.Chip) commentGroups {
+	anchors := buildAnchors(c)
+	g := commentGroups{parts: make([][]description.Comment, c.Parts().Len())}
+
+	for i := 0; i < c.NumComments(); i++ {
+		comment := c.Comment(i)
+
+		if comment.Start() < c.DeclStart() {
+			g.header = append(g.header, comment)
+			continue
+		}
+
+		a := nextAnchor(anchors, comment.Start())
+		switch a.group {
+		case groupInputs:
+			g.inputs = append(g.inputs, comment)
+		case groupOutputs:
+			g.outputs = append(g.outputs, comment)
+		case groupPart:
+			g.parts[a.index] = append(g.parts[a.index], comment)
+		default:
+			g.end = append(g.end, comment)
+		}
+	}
+	return g
+}
+
+// buildAnchors lists, in source order, the start position of every
+// declaration a comment can be reattached to, followed by the position of
+// the chip's closing brace as a catch-all for trailing comments.
+func buildAnchors(c description.Chip) []anchor {
+	var anchors []anchor
+	if inputs := c.Inputs(); inputs.Len() > 0 {
+		anchors = append(anchors, anchor{pos: inputs.At(0).Start(), group: groupInputs})
+	}
+	if outputs := c.Outputs(); outputs.Len() > 0 {
+		anchors = append(anchors, anchor{pos: outputs.At(0).Start(), group: groupOutputs})
+	}
+	parts := c.Parts()
+	for i := 0; i < parts.Len(); i++ {
+		anchors = append(anchors, anchor{pos: parts.At(i).Start(), group: groupPart, index: i})
+	}
+	anchors = append(anchors, anchor{pos: c.End(), group: groupEnd})
+	return anchors
+}
+
+// nextAnchor returns the first anchor starting strictly after pos,
+// i.e. the nearest following declaration.
+func nextAnchor(anchors []anchor, pos token.Pos) anchor {
+	for _, a := range anchors {
+		if a.pos > pos {
+			return a
+		}
+	}
+	return anchor{group: groupEnd}
+}
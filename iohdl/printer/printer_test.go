@@ -0,0 +1,179 @@
+package printer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/batiazinga/hdl/iohdl/internal/description"
+	"github.com/batiazinga/hdl/iohdl/internal/token"
+	"github.com/batiazinga/hdl/iohdl/printer"
+)
+
+func TestFprint(t *testing.T) {
+	var pos token.Pos
+
+	var b description.ChipBuilder
+	b.Declare(pos, pos, "And16")
+	b.DeclareInputs(description.InputList{
+		description.NewInput(pos, pos, "a", 16),
+		description.NewInput(pos, pos, "b", 16),
+	})
+	b.DeclareOutputs(description.OutputList{
+		description.NewOutput(pos, pos, "out", 16),
+	})
+	b.AppendPart(description.NewPart(pos, pos, "Nand16", []description.Connection{
+		description.NewConnection("a", "a"),
+		description.NewConnection("b", "b"),
+		description.NewConnection("out", "out"),
+	}))
+	chip := b.Build()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, chip, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "CHIP And16 {\n" +
+		"\tIN a[16], b[16];\n" +
+		"\tOUT out[16];\n" +
+		"\tPARTS {\n" +
+		"\t\tNand16(a=a, b=b, out=out);\n" +
+		"\t}\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\ninstead of:\n%s", got, want)
+	}
+}
+
+// TestFprintCommentAttachment checks that a comment is printed right before
+// the nearest declaration that follows it in the source, not always at the
+// very top of the chip.
+func TestFprintCommentAttachment(t *testing.T) {
+	var b description.ChipBuilder
+	b.Declare(token.Pos(0), token.Pos(100), "And")
+	b.AppendComment(description.NewComment(token.Pos(40), token.Pos(50), "// about the output"))
+	b.DeclareInputs(description.InputList{
+		description.NewInput(token.Pos(10), token.Pos(11), "a", 1),
+	})
+	b.DeclareOutputs(description.OutputList{
+		description.NewOutput(token.Pos(60), token.Pos(63), "out", 1),
+	})
+	b.AppendPart(description.NewPart(token.Pos(70), token.Pos(80), "Nand", []description.Connection{
+		description.NewConnection("a", "a"),
+	}))
+	chip := b.Build()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, chip, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "CHIP And {\n" +
+		"\tIN a;\n" +
+		"\t// about the output\n" +
+		"\tOUT out;\n" +
+		"\tPARTS {\n" +
+		"\t\tNand(a=a);\n" +
+		"\t}\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\ninstead of:\n%s", got, want)
+	}
+}
+
+// TestFprintNormalizeCase checks that Config.NormalizeCase rewrites odd
+// casings of the true/false literals used as connection values.
+func TestFprintNormalizeCase(t *testing.T) {
+	var b description.ChipBuilder
+	b.Declare(token.Pos(0), token.Pos(0), "Always")
+	b.DeclareOutputs(description.OutputList{
+		description.NewOutput(token.Pos(0), token.Pos(0), "out", 1),
+	})
+	b.AppendPart(description.NewPart(token.Pos(0), token.Pos(0), "Nand", []description.Connection{
+		description.NewConnection("a", "TRUE"),
+		description.NewConnection("b", "TRUE"),
+		description.NewConnection("out", "out"),
+	}))
+	chip := b.Build()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, chip, &printer.Config{NormalizeCase: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "CHIP Always {\n" +
+		"\tOUT out;\n" +
+		"\tPARTS {\n" +
+		"\t\tNand(a=true, b=true, out=out);\n" +
+		"\t}\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\ninstead of:\n%s", got, want)
+	}
+}
+
+// TestFprintMaxWidth checks that a part's connection list wraps onto
+// continuation lines once it would exceed Config.MaxWidth.
+func TestFprintMaxWidth(t *testing.T) {
+	var b description.ChipBuilder
+	b.Declare(token.Pos(0), token.Pos(0), "Mux")
+	b.AppendPart(description.NewPart(token.Pos(0), token.Pos(0), "Mux16", []description.Connection{
+		description.NewConnection("a", "a"),
+		description.NewConnection("b", "b"),
+		description.NewConnection("sel", "sel"),
+		description.NewConnection("out", "out"),
+	}))
+	chip := b.Build()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, chip, &printer.Config{MaxWidth: 20}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "CHIP Mux {\n" +
+		"\tPARTS {\n" +
+		"\t\tMux16(\n" +
+		"\t\t\ta=a,\n" +
+		"\t\t\tb=b,\n" +
+		"\t\t\tsel=sel,\n" +
+		"\t\t\tout=out\n" +
+		"\t\t);\n" +
+		"\t}\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\ninstead of:\n%s", got, want)
+	}
+}
+
+// TestFprintBlankLineGrouping checks that, when Config.FileSet is set, a
+// blank line separating two parts in the source is reproduced in the output.
+func TestFprintBlankLineGrouping(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("test.hdl", 100)
+	file.AddLine(10)
+	file.AddLine(11)
+	file.AddLine(12) // blank line between part 1 and part 2
+	file.AddLine(13)
+
+	var b description.ChipBuilder
+	b.Declare(token.Pos(0), token.Pos(0), "Test")
+	b.AppendPart(description.NewPart(file.Pos(1), file.Pos(2), "A", nil))
+	b.AppendPart(description.NewPart(file.Pos(13), file.Pos(14), "B", nil))
+	chip := b.Build()
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, chip, &printer.Config{FileSet: fset}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "CHIP Test {\n" +
+		"\tPARTS {\n" +
+		"\t\tA();\n" +
+		"\n" +
+		"\t\tB();\n" +
+		"\t}\n" +
+		"}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\ninstead of:\n%s", got, want)
+	}
+}